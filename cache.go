@@ -0,0 +1,144 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	sdk "github.com/conduitio/conduit-processor-sdk"
+)
+
+// Cache is the response cache consulted before Command and Embed calls. It is deliberately
+// narrow so other backends (e.g. Redis, BadgerDB) can be swapped in without touching the model
+// paths.
+type Cache interface {
+	// Get returns the cached value for key, if present and not expired.
+	Get(key string) (value any, ok bool)
+	// Set stores value under key.
+	Set(key string, value any)
+}
+
+// lruCache is the default in-process Cache: an LRU with an optional per-entry TTL and an
+// optional max entry count.
+type lruCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List
+	items      map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// newLRUCache creates a cache that evicts its least recently used entry once it holds more than
+// maxEntries (0 means unlimited), and treats an entry as stale after ttl (0 means it never
+// expires on its own).
+func newLRUCache(maxEntries int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *lruCache) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = &lruEntry{key: key, value: value, expiresAt: expiresAt}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	c.items[key] = elem
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
+
+// cacheGet consults the Processor's cache, if any, logging the outcome at debug level.
+func (p *Processor) cacheGet(ctx context.Context, key string) (any, bool) {
+	if p.cache == nil {
+		return nil, false
+	}
+
+	value, ok := p.cache.Get(key)
+	sdk.Logger(ctx).Debug().Bool("hit", ok).Str("cacheKey", key).Msg("cohere response cache lookup")
+	return value, ok
+}
+
+// cacheSet populates the Processor's cache, if one is configured.
+func (p *Processor) cacheSet(key string, value any) {
+	if p.cache == nil {
+		return
+	}
+	p.cache.Set(key, value)
+}
+
+// cacheKey derives a cache key from the model, its version, the configured inputType (used by
+// embed models, empty otherwise) and the record payload being sent.
+func cacheKey(model, modelVersion, inputType string, payload []byte) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(modelVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(inputType))
+	h.Write([]byte{0})
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
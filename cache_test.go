@@ -0,0 +1,73 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestLRUCache_evictsLeastRecentlyUsed(t *testing.T) {
+	is := is.New(t)
+
+	c := newLRUCache(2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// touch "a" so "b" becomes the least recently used entry.
+	_, ok := c.Get("a")
+	is.True(ok)
+
+	c.Set("c", 3)
+
+	_, ok = c.Get("b")
+	is.True(!ok)
+
+	v, ok := c.Get("a")
+	is.True(ok)
+	is.Equal(v, 1)
+
+	v, ok = c.Get("c")
+	is.True(ok)
+	is.Equal(v, 3)
+}
+
+func TestLRUCache_ttlExpiry(t *testing.T) {
+	is := is.New(t)
+
+	c := newLRUCache(0, time.Millisecond)
+	c.Set("a", 1)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := c.Get("a")
+	is.True(!ok)
+}
+
+func TestCacheKey_stableAndDistinct(t *testing.T) {
+	is := is.New(t)
+
+	k1 := cacheKey(EmbedModel, "embed-v3", "search_document", []byte("hello"))
+	k2 := cacheKey(EmbedModel, "embed-v3", "search_document", []byte("hello"))
+	is.Equal(k1, k2)
+
+	k3 := cacheKey(EmbedModel, "embed-v3", "search_document", []byte("goodbye"))
+	is.True(k1 != k3)
+
+	k4 := cacheKey(CommandModel, "embed-v3", "search_document", []byte("hello"))
+	is.True(k1 != k4)
+}
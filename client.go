@@ -0,0 +1,76 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"net/http"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	cohereClient "github.com/cohere-ai/cohere-go/v2/client"
+	"github.com/cohere-ai/cohere-go/v2/option"
+)
+
+// CohereClient is the subset of the Cohere API this processor depends on. It exists so tests
+// can inject a fake instead of talking to the real API; see the coheretest package.
+type CohereClient interface {
+	Chat(ctx context.Context, request *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error)
+	Embed(ctx context.Context, request *cohere.V2EmbedRequest) (*cohere.EmbedByTypeResponse, error)
+	Rerank(ctx context.Context, request *cohere.V2RerankRequest) (*cohere.RerankResponse, error)
+}
+
+// clientAdapter adapts the generated *cohereClient.Client (whose Chat/Embed/Rerank methods live
+// under a `V2` sub-client) to the CohereClient interface.
+type clientAdapter struct {
+	client *cohereClient.Client
+}
+
+func newClientAdapter(client *cohereClient.Client) CohereClient {
+	return &clientAdapter{client: client}
+}
+
+func (a *clientAdapter) Chat(ctx context.Context, request *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error) {
+	return a.client.V2.Chat(ctx, request)
+}
+
+func (a *clientAdapter) Embed(ctx context.Context, request *cohere.V2EmbedRequest) (*cohere.EmbedByTypeResponse, error) {
+	return a.client.V2.Embed(ctx, request)
+}
+
+func (a *clientAdapter) Rerank(ctx context.Context, request *cohere.V2RerankRequest) (*cohere.RerankResponse, error) {
+	return a.client.V2.Rerank(ctx, request)
+}
+
+// clientOptions builds the cohere-go client options for cfg, applying its authScheme, baseURL
+// and headers so the processor can talk to self-hosted deployments (Bedrock, Azure AI, on-prem)
+// as well as the Cohere SaaS API.
+func clientOptions(cfg ProcessorConfig) []option.RequestOption {
+	header := http.Header{}
+	switch cfg.AuthScheme {
+	case AuthSchemeAPIKey:
+		header.Set("api-key", cfg.APIKey)
+	default: // AuthSchemeBearer
+		header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	for k, v := range cfg.Headers {
+		header.Set(k, v)
+	}
+
+	opts := []option.RequestOption{option.WithHTTPHeader(header)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, option.WithBaseURL(cfg.BaseURL))
+	}
+	return opts
+}
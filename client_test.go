@@ -0,0 +1,76 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cohere-ai/cohere-go/v2/option"
+	"github.com/matryer/is"
+)
+
+// requestOptions applies opts to a zero-value option.RequestOptions so individual fields can be
+// asserted on in tests.
+func requestOptions(opts []option.RequestOption) *option.RequestOptions {
+	options := &option.RequestOptions{HTTPHeader: http.Header{}}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func TestClientOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        ProcessorConfig
+		wantHeader http.Header
+	}{
+		{
+			name: "default bearer scheme",
+			cfg:  ProcessorConfig{APIKey: "secret", AuthScheme: AuthSchemeBearer},
+			wantHeader: http.Header{
+				"Authorization": []string{"Bearer secret"},
+			},
+		},
+		{
+			name: "api-key scheme for self-hosted deployments",
+			cfg:  ProcessorConfig{APIKey: "secret", AuthScheme: AuthSchemeAPIKey},
+			wantHeader: http.Header{
+				"Api-Key": []string{"secret"},
+			},
+		},
+		{
+			name: "custom headers are merged in",
+			cfg: ProcessorConfig{
+				APIKey:     "secret",
+				AuthScheme: AuthSchemeBearer,
+				Headers:    map[string]string{"X-Tenant-Id": "acme"},
+			},
+			wantHeader: http.Header{
+				"Authorization": []string{"Bearer secret"},
+				"X-Tenant-Id":   []string{"acme"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			got := requestOptions(clientOptions(tc.cfg))
+			is.Equal(got.HTTPHeader, tc.wantHeader)
+		})
+	}
+}
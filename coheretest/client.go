@@ -0,0 +1,80 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coheretest provides a scriptable fake of the cohere.CohereClient interface for
+// hermetic unit tests, so model paths can be exercised without talking to the real API.
+package coheretest
+
+import (
+	"context"
+	"sync"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+)
+
+// ChatFunc handles a single Chat call made to a Client fake.
+type ChatFunc func(ctx context.Context, request *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error)
+
+// EmbedFunc handles a single Embed call made to a Client fake.
+type EmbedFunc func(ctx context.Context, request *cohere.V2EmbedRequest) (*cohere.EmbedByTypeResponse, error)
+
+// RerankFunc handles a single Rerank call made to a Client fake.
+type RerankFunc func(ctx context.Context, request *cohere.V2RerankRequest) (*cohere.RerankResponse, error)
+
+// Client is a scriptable fake implementing cohere.CohereClient. Set the *Func fields to control
+// what each call returns; every request is also recorded so tests can assert on call order,
+// count and arguments. A nil *Func returns a zero-value response and no error.
+type Client struct {
+	ChatFunc   ChatFunc
+	EmbedFunc  EmbedFunc
+	RerankFunc RerankFunc
+
+	mu             sync.Mutex
+	ChatRequests   []*cohere.V2ChatRequest
+	EmbedRequests  []*cohere.V2EmbedRequest
+	RerankRequests []*cohere.V2RerankRequest
+}
+
+func (c *Client) Chat(ctx context.Context, request *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error) {
+	c.mu.Lock()
+	c.ChatRequests = append(c.ChatRequests, request)
+	c.mu.Unlock()
+
+	if c.ChatFunc == nil {
+		return &cohere.NonStreamedChatResponse{}, nil
+	}
+	return c.ChatFunc(ctx, request)
+}
+
+func (c *Client) Embed(ctx context.Context, request *cohere.V2EmbedRequest) (*cohere.EmbedByTypeResponse, error) {
+	c.mu.Lock()
+	c.EmbedRequests = append(c.EmbedRequests, request)
+	c.mu.Unlock()
+
+	if c.EmbedFunc == nil {
+		return &cohere.EmbedByTypeResponse{}, nil
+	}
+	return c.EmbedFunc(ctx, request)
+}
+
+func (c *Client) Rerank(ctx context.Context, request *cohere.V2RerankRequest) (*cohere.RerankResponse, error) {
+	c.mu.Lock()
+	c.RerankRequests = append(c.RerankRequests, request)
+	c.mu.Unlock()
+
+	if c.RerankFunc == nil {
+		return &cohere.RerankResponse{}, nil
+	}
+	return c.RerankFunc(ctx, request)
+}
@@ -16,6 +16,7 @@ package cohere
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -40,6 +41,28 @@ var allowedInputTypes = map[string]bool{
 	"image":           true,
 }
 
+// allowed values for rerank modelVersion.
+var allowedRerankModelVersions = map[string]bool{
+	"rerank-english-v3.0":      true,
+	"rerank-multilingual-v3.0": true,
+}
+
+// maxEmbedBatchSize is the maximum number of texts Cohere's Embed endpoint accepts per call.
+const maxEmbedBatchSize = 96
+
+// auth schemes accepted for authScheme. `api-key` sends the key in an `api-key` header, as
+// expected by Azure AI; `bearer` sends it as `Authorization: Bearer <key>`, as expected by the
+// Cohere SaaS API and Bedrock.
+const (
+	AuthSchemeAPIKey = "api-key"
+	AuthSchemeBearer = "bearer"
+)
+
+var allowedAuthSchemes = map[string]bool{
+	AuthSchemeAPIKey: true,
+	AuthSchemeBearer: true,
+}
+
 type ProcessorConfig struct {
 	// Model is one of the Cohere model (command,embed,rerank).
 	Model string `json:"model" validate:"required" default:"command"`
@@ -55,10 +78,39 @@ type ProcessorConfig struct {
 	BackoffRetryMin time.Duration `json:"backoffRetry.min" default:"100ms"`
 	// The maximum waiting time before retrying.
 	BackoffRetryMax time.Duration `json:"backoffRetry.max" default:"5s"`
+	// Whether to randomize each backoff wait with full jitter, instead of waiting a fixed,
+	// deterministic duration on every retry.
+	BackoffRetryJitter bool `json:"backoffRetry.jitter" default:"true"`
 	// Specifies in which field should the response body be saved.
 	ResponseBodyRef string `json:"response.body" default:".Payload.After"`
+	// BaseURL overrides the default Cohere SaaS API endpoint, for self-hosted deployments
+	// (Bedrock, Azure AI, on-prem). Must be an absolute URL when set.
+	//
+	// Model version availability depends on the backend: Bedrock and Azure AI currently only
+	// serve `command-r`, `command-r-plus` and the `embed-*-v3.0` family; `rerank-*` models are
+	// SaaS-only.
+	BaseURL string `json:"baseURL"`
+	// AuthScheme selects how apiKey is sent to BaseURL. Allowed values: api-key, bearer.
+	AuthScheme string `json:"authScheme" default:"bearer"`
+	// Headers are additional HTTP headers sent with every request, useful for private
+	// deployments that require e.g. a tenant or deployment identifier.
+	Headers map[string]string `json:"headers"`
 	// Config specific to embed model
 	EmbedConfig *EmbedConfig `json:"embedConfig"`
+	// Config specific to rerank model
+	RerankConfig *RerankConfig `json:"rerankConfig"`
+	// Config for the response cache consulted before Command and Embed calls.
+	CacheConfig CacheConfig `json:"cache"`
+}
+
+type CacheConfig struct {
+	// Enabled turns on the in-process response cache for Command and Embed calls.
+	Enabled bool `json:"enabled" default:"false"`
+	// MaxEntries caps the number of cached responses kept in memory, evicting the least
+	// recently used entry once exceeded. 0 means unlimited.
+	MaxEntries int `json:"maxEntries" default:"1000" validate:"gt=-1"`
+	// TTL is how long a cached response stays valid. 0 means entries never expire on their own.
+	TTL time.Duration `json:"ttl" default:"0"`
 }
 
 type EmbedConfig struct {
@@ -68,14 +120,51 @@ type EmbedConfig struct {
 	// Specifies the types of embeddings you want to get back. Can be one or more of the allowed values.
 	// Allowed values: float, int8, uint8, binary, ubinary.
 	EmbeddingTypes []string `json:"embeddingTypes"`
+	// BatchSize is the number of texts sent in a single Embed API call. Cohere's Embed endpoint
+	// accepts up to 96 texts per call.
+	BatchSize int `json:"batchSize" default:"96" validate:"gt=0"`
+	// MaxTokensPerBatch caps the estimated total token count accumulated in a single batch, in
+	// addition to batchSize. A batch is cut short as soon as adding the next record would exceed
+	// it. 0 means no limit is applied.
+	MaxTokensPerBatch int `json:"maxTokensPerBatch" default:"0"`
+}
+
+type RerankConfig struct {
+	// Query is a reference resolver expression pointing at the field holding the search query.
+	Query string `json:"query" validate:"required"`
+	// Documents is a reference resolver expression pointing at the field holding the list of
+	// documents to rerank. Mutually exclusive with `documentsList`.
+	Documents string `json:"documents"`
+	// DocumentsList is a static list of documents to rerank, used when `documents` is not set.
+	DocumentsList []string `json:"documentsList"`
+	// TopN limits the number of most relevant documents returned. If unset, all documents are returned.
+	TopN int `json:"topN"`
+	// MaxChunksPerDoc is the maximum number of chunks a document is split into for scoring.
+	MaxChunksPerDoc int `json:"maxChunksPerDoc"`
 }
 
 // Validate executes manual validations beyond what is defined in struct tags.
 func (c ProcessorConfig) Validate() error {
-	if c.Model == EmbedModel {
+	if !allowedAuthSchemes[c.AuthScheme] {
+		return fmt.Errorf("invalid authScheme: %s", c.AuthScheme)
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || !u.IsAbs() {
+			return fmt.Errorf("baseURL must be an absolute URL: %s", c.BaseURL)
+		}
+	}
+
+	switch c.Model {
+	case EmbedModel:
 		if err := validateEmbedModel(c); err != nil {
 			return err
 		}
+	case RerankModel:
+		if err := validateRerankModel(c); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -109,5 +198,38 @@ func validateEmbedModel(c ProcessorConfig) error {
 		}
 	}
 
+	// validate `batchSize` against Cohere's Embed endpoint limit.
+	if c.EmbedConfig.BatchSize > maxEmbedBatchSize {
+		return fmt.Errorf("batchSize must not exceed %d", maxEmbedBatchSize)
+	}
+
+	return nil
+}
+
+// validateRerankModel validates configurations specific to the rerank model.
+func validateRerankModel(c ProcessorConfig) error {
+	// validate `modelVersion` for rerank model.
+	if !allowedRerankModelVersions[c.ModelVersion] {
+		return fmt.Errorf("invalid modelVersion for rerank model: %s", c.ModelVersion)
+	}
+
+	// ensure `rerankConfig` is provided.
+	if c.RerankConfig == nil {
+		return fmt.Errorf("rerankConfig is required when model is 'rerank'")
+	}
+
+	// ensure `query` is provided.
+	if c.RerankConfig.Query == "" {
+		return fmt.Errorf("rerankConfig.query is required")
+	}
+
+	// ensure exactly one of `documents` or `documentsList` is provided.
+	if c.RerankConfig.Documents == "" && len(c.RerankConfig.DocumentsList) == 0 {
+		return fmt.Errorf("either rerankConfig.documents or rerankConfig.documentsList must be provided")
+	}
+	if c.RerankConfig.Documents != "" && len(c.RerankConfig.DocumentsList) > 0 {
+		return fmt.Errorf("only one of rerankConfig.documents or rerankConfig.documentsList may be provided")
+	}
+
 	return nil
 }
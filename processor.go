@@ -29,10 +29,13 @@ type Processor struct {
 	sdk.UnimplementedProcessor
 
 	responseBodyRef *sdk.ReferenceResolver
+	queryRef        *sdk.ReferenceResolver
+	documentsRef    *sdk.ReferenceResolver
 
 	config     ProcessorConfig
 	backoffCfg *backoff.Backoff
-	client     *cohereClient.Client
+	client     CohereClient
+	cache      Cache
 }
 
 const (
@@ -41,9 +44,34 @@ const (
 	RerankModel  = "rerank"
 )
 
-func NewProcessor() sdk.Processor {
+// ProcessorOption configures a Processor created via NewProcessor.
+type ProcessorOption func(*Processor)
+
+// WithClient overrides the Cohere client the Processor talks to. It exists so tests can inject
+// a fake (see coheretest) instead of hitting the real API; Configure leaves a client set this
+// way untouched.
+func WithClient(client CohereClient) ProcessorOption {
+	return func(p *Processor) {
+		p.client = client
+	}
+}
+
+// WithCache overrides the response cache the Processor consults before Command and Embed calls.
+// Configure leaves a cache set this way untouched, even if cache.enabled is false.
+func WithCache(cache Cache) ProcessorOption {
+	return func(p *Processor) {
+		p.cache = cache
+	}
+}
+
+func NewProcessor(opts ...ProcessorOption) sdk.Processor {
+	p := &Processor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
 	// Create Processor and wrap it in the default middleware.
-	return sdk.ProcessorWithMiddleware(&Processor{}, sdk.DefaultProcessorMiddleware()...)
+	return sdk.ProcessorWithMiddleware(p, sdk.DefaultProcessorMiddleware()...)
 }
 
 func (p *Processor) Configure(ctx context.Context, cfg config.Config) error {
@@ -68,18 +96,47 @@ func (p *Processor) Configure(ctx context.Context, cfg config.Config) error {
 	}
 	p.responseBodyRef = &responseBodyRef
 
-	// new cohere client
-	p.client = cohereClient.NewClient()
+	if p.config.Model == RerankModel && p.config.RerankConfig != nil {
+		if p.config.RerankConfig.Query != "" {
+			queryRef, err := sdk.NewReferenceResolver(p.config.RerankConfig.Query)
+			if err != nil {
+				return fmt.Errorf("failed parsing rerankConfig.query %v: %w", p.config.RerankConfig.Query, err)
+			}
+			p.queryRef = &queryRef
+		}
+
+		if p.config.RerankConfig.Documents != "" {
+			documentsRef, err := sdk.NewReferenceResolver(p.config.RerankConfig.Documents)
+			if err != nil {
+				return fmt.Errorf("failed parsing rerankConfig.documents %v: %w", p.config.RerankConfig.Documents, err)
+			}
+			p.documentsRef = &documentsRef
+		}
+	}
+
+	// new cohere client, unless a fake was already injected via WithClient
+	if p.client == nil {
+		p.client = newClientAdapter(cohereClient.NewClient(clientOptions(p.config)...))
+	}
 
-	p.backoffCfg = &backoff.Backoff{
-		Factor: p.config.BackoffRetryFactor,
-		Min:    p.config.BackoffRetryMin,
-		Max:    p.config.BackoffRetryMax,
+	if p.cache == nil && p.config.CacheConfig.Enabled {
+		p.cache = newLRUCache(p.config.CacheConfig.MaxEntries, p.config.CacheConfig.TTL)
 	}
 
+	p.backoffCfg = newBackoffCfg(p.config)
+
 	return nil
 }
 
+// newBackoffCfg builds the backoff.Backoff used to compute retry wait caps from cfg.
+func newBackoffCfg(cfg ProcessorConfig) *backoff.Backoff {
+	return &backoff.Backoff{
+		Factor: cfg.BackoffRetryFactor,
+		Min:    cfg.BackoffRetryMin,
+		Max:    cfg.BackoffRetryMax,
+	}
+}
+
 func (p *Processor) Specification() (sdk.Specification, error) {
 	// Specification contains the metadata for the processor, which can be used to define how
 	// to reference the processor, describe what the processor does and the configuration
@@ -138,3 +195,16 @@ func (p *Processor) setField(r *opencdc.Record, refRes *sdk.ReferenceResolver, d
 
 	return nil
 }
+
+func (p *Processor) getField(r *opencdc.Record, refRes *sdk.ReferenceResolver) (any, error) {
+	if refRes == nil {
+		return nil, nil
+	}
+
+	ref, err := refRes.Resolve(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reference resolver: %w", err)
+	}
+
+	return ref.Get(), nil
+}
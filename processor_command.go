@@ -26,48 +26,48 @@ import (
 func (p *Processor) processCommandModel(ctx context.Context, records []opencdc.Record) []sdk.ProcessedRecord {
 	out := make([]sdk.ProcessedRecord, 0, len(records))
 	for _, record := range records {
-		resp, err := p.client.V2.Chat(
-			ctx,
-			&cohere.V2ChatRequest{
-				Model: p.config.ModelVersion,
-				Messages: cohere.ChatMessages{
-					{
-						Role: "user",
-						User: &cohere.UserMessage{Content: &cohere.UserMessageContent{
-							String: string(record.Payload.After.Bytes()),
-						}},
+		text := string(record.Payload.After.Bytes())
+		key := cacheKey(CommandModel, p.config.ModelVersion, "", []byte(text))
+
+		if cached, ok := p.cacheGet(ctx, key); ok {
+			if err := p.setField(&record, p.responseBodyRef, cached); err != nil {
+				return append(out, sdk.ErrorRecord{Error: fmt.Errorf("failed setting response body: %w", err)})
+			}
+			out = append(out, sdk.SingleRecord(record))
+			continue
+		}
+
+		var responseBody string
+		err := p.callWithRetry(ctx, func() error {
+			resp, err := p.client.Chat(
+				ctx,
+				&cohere.V2ChatRequest{
+					Model: p.config.ModelVersion,
+					Messages: cohere.ChatMessages{
+						{
+							Role: "user",
+							User: &cohere.UserMessage{Content: &cohere.UserMessageContent{
+								String: text,
+							}},
+						},
 					},
 				},
-			},
-		)
+			)
+			if err != nil {
+				return err
+			}
+			responseBody = resp.String()
+			return nil
+		})
 		if err != nil {
 			return append(out, sdk.ErrorRecord{Error: err})
 		}
+		p.cacheSet(key, responseBody)
 
-		err = p.setField(&record, p.referenceResolver, resp.String())
-		if err != nil {
+		if err := p.setField(&record, p.responseBodyRef, responseBody); err != nil {
 			return append(out, sdk.ErrorRecord{Error: fmt.Errorf("failed setting response body: %w", err)})
 		}
-
 		out = append(out, sdk.SingleRecord(record))
 	}
 	return out
 }
-
-func (p *Processor) setField(r *opencdc.Record, refRes *sdk.ReferenceResolver, data any) error {
-	if refRes == nil {
-		return nil
-	}
-
-	ref, err := refRes.Resolve(r)
-	if err != nil {
-		return fmt.Errorf("error reference resolver: %w", err)
-	}
-
-	err = ref.Set(data)
-	if err != nil {
-		return fmt.Errorf("error reference set: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,126 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"testing"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	"github.com/conduitio-labs/conduit-processor-cohere/coheretest"
+	sdk "github.com/conduitio/conduit-processor-sdk"
+	"github.com/matryer/is"
+)
+
+func TestProcessor_processCommandModel(t *testing.T) {
+	is := is.New(t)
+
+	fake := &coheretest.Client{
+		ChatFunc: func(_ context.Context, _ *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error) {
+			return &cohere.NonStreamedChatResponse{}, nil
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion:    "command",
+			ResponseBodyRef: ".Payload.After",
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(p.config.ResponseBodyRef)
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	records := newTestRecords(1, "tell me a joke")
+	out := p.processCommandModel(context.Background(), records)
+
+	is.Equal(len(out), 1)
+	is.Equal(len(fake.ChatRequests), 1)
+
+	single, ok := out[0].(sdk.SingleRecord)
+	is.True(ok)
+	is.True(len(single.Payload.After.Bytes()) > 0)
+}
+
+func TestProcessor_processCommandModel_retriesOnServerError(t *testing.T) {
+	is := is.New(t)
+
+	attempts := 0
+	fake := &coheretest.Client{
+		ChatFunc: func(_ context.Context, _ *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error) {
+			attempts++
+			if attempts < 2 {
+				return nil, &cohere.InternalServerError{}
+			}
+			return &cohere.NonStreamedChatResponse{}, nil
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion:       "command",
+			ResponseBodyRef:    ".Payload.After",
+			BackoffRetryCount:  3,
+			BackoffRetryFactor: 2,
+			BackoffRetryMin:    0,
+			BackoffRetryMax:    0,
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(p.config.ResponseBodyRef)
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	out := p.processCommandModel(context.Background(), newTestRecords(1, "hi"))
+
+	is.Equal(len(out), 1)
+	is.Equal(attempts, 2)
+	_, ok := out[0].(sdk.SingleRecord)
+	is.True(ok)
+}
+
+func TestProcessor_processCommandModel_cacheHitSkipsTheAPICall(t *testing.T) {
+	is := is.New(t)
+
+	calls := 0
+	fake := &coheretest.Client{
+		ChatFunc: func(_ context.Context, _ *cohere.V2ChatRequest) (*cohere.NonStreamedChatResponse, error) {
+			calls++
+			return &cohere.NonStreamedChatResponse{}, nil
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		cache:  newLRUCache(0, 0),
+		config: ProcessorConfig{
+			ModelVersion:    "command",
+			ResponseBodyRef: ".Payload.After",
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(p.config.ResponseBodyRef)
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	records := newTestRecords(1, "tell me a joke")
+	p.processCommandModel(context.Background(), records)
+	p.processCommandModel(context.Background(), records)
+
+	is.Equal(calls, 1)
+}
@@ -16,74 +16,169 @@ package cohere
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"time"
 
 	cohere "github.com/cohere-ai/cohere-go/v2"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-processor-sdk"
 )
 
+// avgCharsPerToken is a rough, model-agnostic heuristic used to estimate how many tokens a
+// batch will cost before sending it, so maxTokensPerBatch can be enforced client-side.
+const avgCharsPerToken = 4
+
 func (p *Processor) processEmbedModel(ctx context.Context, records []opencdc.Record) []sdk.ProcessedRecord {
-	out := make([]sdk.ProcessedRecord, 0, len(records))
-	for _, record := range records {
-		for {
-			req := &cohere.V2EmbedRequest{
-				Model:          p.config.ModelVersion,
-				Texts:          []string{string(record.Payload.After.Bytes())},
-				EmbeddingTypes: p.getEmbeddingTypes(),
-			}
-			if p.config.EmbedConfig.InputType != "" {
-				req.InputType = cohere.EmbedInputType(p.config.EmbedConfig.InputType)
-			}
+	out := make([]sdk.ProcessedRecord, len(records))
 
-			resp, err := p.client.V2.Embed(ctx, req)
-			attempt := p.backoffCfg.Attempt()
-			duration := p.backoffCfg.Duration()
-			if err != nil {
-				switch {
-				case errors.As(err, &cohere.GatewayTimeoutError{}),
-					errors.As(err, &cohere.InternalServerError{}),
-					errors.As(err, &cohere.ServiceUnavailableError{}):
-
-					if attempt < p.config.BackoffRetryCount {
-						sdk.Logger(ctx).Debug().
-							Err(err).
-							Float64("attempt", attempt).
-							Float64("backoffRetry.count", p.config.BackoffRetryCount).
-							Int64("backoffRetry.duration", duration.Milliseconds()).
-							Msg("retrying Cohere HTTP request")
-
-						select {
-						case <-ctx.Done():
-							return append(out, sdk.ErrorRecord{Error: ctx.Err()})
-						case <-time.After(duration):
-							continue
-						}
-					} else {
-						return append(out, sdk.ErrorRecord{Error: err})
-					}
-
-				default:
-					// BadRequestError, ClientClosedRequestError, ForbiddenError, InvalidTokenError,
-					// NotFoundError, NotImplementedError, TooManyRequestsError, UnauthorizedError, UnprocessableEntityError
-					return append(out, sdk.ErrorRecord{Error: err})
-				}
+	// Serve whatever is already cached, and collect the rest for batching.
+	misses := make([]opencdc.Record, 0, len(records))
+	missIdx := make([]int, 0, len(records))
+	for i, record := range records {
+		key := p.embedCacheKey(string(record.Payload.After.Bytes()))
+		if cached, ok := p.cacheGet(ctx, key); ok {
+			if err := p.setField(&record, p.responseBodyRef, cached); err != nil {
+				out[i] = sdk.ErrorRecord{Error: fmt.Errorf("failed setting response body: %w", err)}
+				continue
 			}
+			out[i] = sdk.SingleRecord(record)
+			continue
+		}
+		misses = append(misses, record)
+		missIdx = append(missIdx, i)
+	}
 
-			p.backoffCfg.Reset() // reset for next processor execution
+	offset := 0
+	for _, batch := range p.embedBatches(misses) {
+		batchOut, ok := p.processEmbedBatch(ctx, batch)
+		if !ok {
+			// The batch failed outright (after exhausting retries, or because the context was
+			// cancelled); batchOut holds a single error record for the first item of this batch,
+			// so stop right there, same as processCommandModel and processRerankModel do on
+			// their first error.
+			idx := missIdx[offset]
+			out[idx] = batchOut[0]
+			return out[:idx+1]
+		}
 
-			err = p.setField(&record, p.responseBodyRef, resp.GetEmbeddings())
-			if err != nil {
-				return append(out, sdk.ErrorRecord{Error: fmt.Errorf("failed setting response body: %w", err)})
-			}
-			out = append(out, sdk.SingleRecord(record))
+		for j, result := range batchOut {
+			out[missIdx[offset+j]] = result
 		}
+		offset += len(batch)
 	}
 	return out
 }
 
+// embedCacheKey derives the cache key for a single embed input.
+func (p *Processor) embedCacheKey(text string) string {
+	return cacheKey(EmbedModel, p.config.ModelVersion, p.config.EmbedConfig.InputType, []byte(text))
+}
+
+// embedBatches groups records into batches of at most embedConfig.batchSize records, cutting a
+// batch short as soon as adding the next record would exceed embedConfig.maxTokensPerBatch.
+func (p *Processor) embedBatches(records []opencdc.Record) [][]opencdc.Record {
+	batchSize := p.config.EmbedConfig.BatchSize
+	maxTokens := p.config.EmbedConfig.MaxTokensPerBatch
+
+	batches := make([][]opencdc.Record, 0, len(records)/batchSize+1)
+	batch := make([]opencdc.Record, 0, batchSize)
+	tokens := 0
+
+	for _, record := range records {
+		recordTokens := estimateTokens(record.Payload.After.Bytes())
+
+		if len(batch) > 0 && (len(batch) >= batchSize || (maxTokens > 0 && tokens+recordTokens > maxTokens)) {
+			batches = append(batches, batch)
+			batch = make([]opencdc.Record, 0, batchSize)
+			tokens = 0
+		}
+
+		batch = append(batch, record)
+		tokens += recordTokens
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+func estimateTokens(payload []byte) int {
+	return len(payload)/avgCharsPerToken + 1
+}
+
+// processEmbedBatch sends a single Embed request for the given batch and fans the resulting
+// embeddings back to each record, preserving input order. The second return value is false if
+// the batch failed outright (the retries were exhausted, or the context was cancelled), in
+// which case the returned slice holds a single error record for the batch and the caller must
+// stop processing further batches, same as processCommandModel and processRerankModel do on
+// their first error.
+func (p *Processor) processEmbedBatch(ctx context.Context, batch []opencdc.Record) ([]sdk.ProcessedRecord, bool) {
+	out := make([]sdk.ProcessedRecord, 0, len(batch))
+	texts := make([]string, len(batch))
+	for i, record := range batch {
+		texts[i] = string(record.Payload.After.Bytes())
+	}
+
+	var embeddings *cohere.EmbedByTypeResponseEmbeddings
+	err := p.callWithRetry(ctx, func() error {
+		req := &cohere.V2EmbedRequest{
+			Model:          p.config.ModelVersion,
+			Texts:          texts,
+			EmbeddingTypes: p.getEmbeddingTypes(),
+		}
+		if p.config.EmbedConfig.InputType != "" {
+			req.InputType = cohere.EmbedInputType(p.config.EmbedConfig.InputType)
+		}
+
+		resp, err := p.client.Embed(ctx, req)
+		if err != nil {
+			return err
+		}
+		embeddings = resp.GetEmbeddings()
+		return nil
+	})
+	if err != nil {
+		return append(out, sdk.ErrorRecord{Error: err}), false
+	}
+
+	for i, record := range batch {
+		embedding := embeddingAtIndex(embeddings, i)
+
+		setErr := p.setField(&record, p.responseBodyRef, embedding)
+		if setErr != nil {
+			return []sdk.ProcessedRecord{sdk.ErrorRecord{Error: fmt.Errorf("failed setting response body: %w", setErr)}}, false
+		}
+		p.cacheSet(p.embedCacheKey(texts[i]), embedding)
+		out = append(out, sdk.SingleRecord(record))
+	}
+	return out, true
+}
+
+// embeddingAtIndex extracts the i-th embedding from a batched EmbedByTypeResponseEmbeddings,
+// keyed the same way as embedConfig.embeddingTypes.
+func embeddingAtIndex(embeddings *cohere.EmbedByTypeResponseEmbeddings, i int) map[string]any {
+	result := map[string]any{}
+	if embeddings == nil {
+		return result
+	}
+	if len(embeddings.Float) > i {
+		result["float"] = embeddings.Float[i]
+	}
+	if len(embeddings.Int8) > i {
+		result["int8"] = embeddings.Int8[i]
+	}
+	if len(embeddings.Uint8) > i {
+		result["uint8"] = embeddings.Uint8[i]
+	}
+	if len(embeddings.Binary) > i {
+		result["binary"] = embeddings.Binary[i]
+	}
+	if len(embeddings.Ubinary) > i {
+		result["ubinary"] = embeddings.Ubinary[i]
+	}
+	return result
+}
+
 func (p *Processor) getEmbeddingTypes() []cohere.EmbeddingType {
 	embeddingTypes := []cohere.EmbeddingType{}
 	for _, et := range p.config.EmbedConfig.EmbeddingTypes {
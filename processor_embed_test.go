@@ -0,0 +1,157 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"testing"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	"github.com/conduitio-labs/conduit-processor-cohere/coheretest"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-processor-sdk"
+	"github.com/matryer/is"
+)
+
+func newTestRecords(n int, payload string) []opencdc.Record {
+	records := make([]opencdc.Record, n)
+	for i := range records {
+		records[i] = opencdc.Record{
+			Payload: opencdc.Change{After: opencdc.RawData(payload)},
+		}
+	}
+	return records
+}
+
+func TestProcessor_embedBatches(t *testing.T) {
+	tests := []struct {
+		name              string
+		batchSize         int
+		maxTokensPerBatch int
+		recordCount       int
+		wantBatchSizes    []int
+	}{
+		{
+			name:           "single batch under batchSize",
+			batchSize:      96,
+			recordCount:    3,
+			wantBatchSizes: []int{3},
+		},
+		{
+			name:           "splits once batchSize is reached",
+			batchSize:      2,
+			recordCount:    5,
+			wantBatchSizes: []int{2, 2, 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			p := &Processor{
+				config: ProcessorConfig{
+					EmbedConfig: &EmbedConfig{
+						BatchSize:         tc.batchSize,
+						MaxTokensPerBatch: tc.maxTokensPerBatch,
+					},
+				},
+			}
+
+			batches := p.embedBatches(newTestRecords(tc.recordCount, "hello"))
+
+			gotSizes := make([]int, len(batches))
+			for i, batch := range batches {
+				gotSizes[i] = len(batch)
+			}
+			is.Equal(gotSizes, tc.wantBatchSizes)
+		})
+	}
+}
+
+func TestProcessor_processEmbedModel(t *testing.T) {
+	is := is.New(t)
+
+	fake := &coheretest.Client{
+		EmbedFunc: func(_ context.Context, req *cohere.V2EmbedRequest) (*cohere.EmbedByTypeResponse, error) {
+			embeddings := &cohere.EmbedByTypeResponseEmbeddings{}
+			for range req.Texts {
+				embeddings.Float = append(embeddings.Float, []float64{0.1, 0.2})
+			}
+			return &cohere.EmbedByTypeResponse{Embeddings: embeddings}, nil
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion:    "embed-v3",
+			ResponseBodyRef: ".Payload.After",
+			EmbedConfig: &EmbedConfig{
+				InputType:      "search_document",
+				EmbeddingTypes: []string{"float"},
+				BatchSize:      96,
+			},
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(p.config.ResponseBodyRef)
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	records := newTestRecords(2, "some text")
+	out := p.processEmbedModel(context.Background(), records)
+
+	is.Equal(len(out), 2)
+	is.Equal(len(fake.EmbedRequests), 1)
+	is.Equal(len(fake.EmbedRequests[0].Texts), 2)
+}
+
+// TestProcessor_processEmbedModel_truncatesOnBatchFailure ensures a batch that fails after
+// exhausting retries stops the whole call and returns a slice truncated at that batch's first
+// record, same as processCommandModel and processRerankModel do on their first error - it must
+// not keep going and report later, unrelated batches as successes.
+func TestProcessor_processEmbedModel_truncatesOnBatchFailure(t *testing.T) {
+	is := is.New(t)
+
+	fake := &coheretest.Client{
+		EmbedFunc: func(_ context.Context, _ *cohere.V2EmbedRequest) (*cohere.EmbedByTypeResponse, error) {
+			return nil, &cohere.InternalServerError{}
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion: "embed-v3",
+			EmbedConfig: &EmbedConfig{
+				InputType:      "search_document",
+				EmbeddingTypes: []string{"float"},
+				BatchSize:      1,
+			},
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(".Payload.After")
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	records := newTestRecords(3, "some text")
+	out := p.processEmbedModel(context.Background(), records)
+
+	is.Equal(len(out), 1)
+	_, ok := out[0].(sdk.ErrorRecord)
+	is.True(ok)
+}
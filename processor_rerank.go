@@ -0,0 +1,134 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"fmt"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-processor-sdk"
+)
+
+// rerankResult is the shape written back through responseBodyRef for every ranked document.
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevanceScore"`
+}
+
+func (p *Processor) processRerankModel(ctx context.Context, records []opencdc.Record) []sdk.ProcessedRecord {
+	out := make([]sdk.ProcessedRecord, 0, len(records))
+	for _, record := range records {
+		query, err := p.rerankQuery(&record)
+		if err != nil {
+			return append(out, sdk.ErrorRecord{Error: err})
+		}
+
+		documents, err := p.rerankDocuments(&record)
+		if err != nil {
+			return append(out, sdk.ErrorRecord{Error: err})
+		}
+
+		req := &cohere.V2RerankRequest{
+			Model:     p.config.ModelVersion,
+			Query:     query,
+			Documents: documents,
+		}
+		if p.config.RerankConfig.TopN > 0 {
+			topN := p.config.RerankConfig.TopN
+			req.TopN = &topN
+		}
+		if p.config.RerankConfig.MaxChunksPerDoc > 0 {
+			maxChunksPerDoc := p.config.RerankConfig.MaxChunksPerDoc
+			req.MaxChunksPerDoc = &maxChunksPerDoc
+		}
+
+		err = p.callWithRetry(ctx, func() error {
+			resp, err := p.client.Rerank(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			results := make([]rerankResult, 0, len(resp.Results))
+			for _, r := range resp.Results {
+				results = append(results, rerankResult{
+					Index:          r.Index,
+					RelevanceScore: r.RelevanceScore,
+				})
+			}
+
+			if err := p.setField(&record, p.responseBodyRef, results); err != nil {
+				return fmt.Errorf("failed setting response body: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return append(out, sdk.ErrorRecord{Error: err})
+		}
+
+		out = append(out, sdk.SingleRecord(record))
+	}
+	return out
+}
+
+// rerankQuery resolves the query text for a record, either from the configured reference
+// or, if none is set, from the record payload itself.
+func (p *Processor) rerankQuery(record *opencdc.Record) (string, error) {
+	if p.queryRef == nil {
+		return string(record.Payload.After.Bytes()), nil
+	}
+
+	val, err := p.getField(record, p.queryRef)
+	if err != nil {
+		return "", fmt.Errorf("failed resolving rerankConfig.query: %w", err)
+	}
+
+	query, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("rerankConfig.query does not reference a string field")
+	}
+	return query, nil
+}
+
+// rerankDocuments resolves the documents to rerank for a record, preferring the static
+// `documentsList` configuration when the `documents` reference is not set.
+func (p *Processor) rerankDocuments(record *opencdc.Record) ([]string, error) {
+	if p.documentsRef == nil {
+		return p.config.RerankConfig.DocumentsList, nil
+	}
+
+	val, err := p.getField(record, p.documentsRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving rerankConfig.documents: %w", err)
+	}
+
+	switch docs := val.(type) {
+	case []string:
+		return docs, nil
+	case []any:
+		out := make([]string, 0, len(docs))
+		for _, d := range docs {
+			s, ok := d.(string)
+			if !ok {
+				return nil, fmt.Errorf("rerankConfig.documents contains a non-string element")
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("rerankConfig.documents does not reference a list of strings")
+	}
+}
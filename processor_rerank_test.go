@@ -0,0 +1,206 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"testing"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	"github.com/conduitio-labs/conduit-processor-cohere/coheretest"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-processor-sdk"
+	"github.com/matryer/is"
+)
+
+func TestProcessor_processRerankModel(t *testing.T) {
+	is := is.New(t)
+
+	fake := &coheretest.Client{
+		RerankFunc: func(_ context.Context, req *cohere.V2RerankRequest) (*cohere.RerankResponse, error) {
+			is.Equal(req.Query, "tell me about dogs")
+			is.Equal(req.Documents, []string{"doc1", "doc2"})
+			return &cohere.RerankResponse{
+				Results: []*cohere.RerankResponseResultsItem{
+					{Index: 1, RelevanceScore: 0.9},
+					{Index: 0, RelevanceScore: 0.1},
+				},
+			}, nil
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion:    "rerank-english-v3.0",
+			ResponseBodyRef: ".Payload.After",
+			RerankConfig: &RerankConfig{
+				DocumentsList: []string{"doc1", "doc2"},
+			},
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(p.config.ResponseBodyRef)
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	records := newTestRecords(1, "tell me about dogs")
+	out := p.processRerankModel(context.Background(), records)
+
+	is.Equal(len(out), 1)
+	is.Equal(len(fake.RerankRequests), 1)
+
+	single, ok := out[0].(sdk.SingleRecord)
+	is.True(ok)
+	is.True(len(single.Payload.After.Bytes()) > 0)
+}
+
+func TestProcessor_processRerankModel_retriesOnServerError(t *testing.T) {
+	is := is.New(t)
+
+	attempts := 0
+	fake := &coheretest.Client{
+		RerankFunc: func(_ context.Context, _ *cohere.V2RerankRequest) (*cohere.RerankResponse, error) {
+			attempts++
+			if attempts < 2 {
+				return nil, &cohere.InternalServerError{}
+			}
+			return &cohere.RerankResponse{}, nil
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion:    "rerank-english-v3.0",
+			ResponseBodyRef: ".Payload.After",
+			RerankConfig: &RerankConfig{
+				DocumentsList: []string{"doc1", "doc2"},
+			},
+			BackoffRetryCount:  3,
+			BackoffRetryFactor: 2,
+			BackoffRetryMin:    0,
+			BackoffRetryMax:    0,
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(p.config.ResponseBodyRef)
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	out := p.processRerankModel(context.Background(), newTestRecords(1, "hi"))
+
+	is.Equal(len(out), 1)
+	is.Equal(attempts, 2)
+	_, ok := out[0].(sdk.SingleRecord)
+	is.True(ok)
+}
+
+func TestProcessor_processRerankModel_stopsOnFirstError(t *testing.T) {
+	is := is.New(t)
+
+	fake := &coheretest.Client{
+		RerankFunc: func(_ context.Context, _ *cohere.V2RerankRequest) (*cohere.RerankResponse, error) {
+			return nil, &cohere.InternalServerError{}
+		},
+	}
+
+	p := &Processor{
+		client: fake,
+		config: ProcessorConfig{
+			ModelVersion: "rerank-english-v3.0",
+			RerankConfig: &RerankConfig{
+				DocumentsList: []string{"doc1", "doc2"},
+			},
+		},
+	}
+	responseBodyRef, err := sdk.NewReferenceResolver(".Payload.After")
+	is.NoErr(err)
+	p.responseBodyRef = &responseBodyRef
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	out := p.processRerankModel(context.Background(), newTestRecords(3, "hi"))
+
+	is.Equal(len(out), 1)
+	_, ok := out[0].(sdk.ErrorRecord)
+	is.True(ok)
+}
+
+func TestProcessor_rerankDocuments(t *testing.T) {
+	tests := []struct {
+		name     string
+		val      any
+		wantDocs []string
+		wantErr  string
+	}{
+		{
+			name:     "string slice",
+			val:      []string{"a", "b"},
+			wantDocs: []string{"a", "b"},
+		},
+		{
+			name:     "any slice of strings",
+			val:      []any{"a", "b"},
+			wantDocs: []string{"a", "b"},
+		},
+		{
+			name:    "any slice with a non-string element",
+			val:     []any{"a", 1},
+			wantErr: "rerankConfig.documents contains a non-string element",
+		},
+		{
+			name:    "unsupported type",
+			val:     "not-a-list",
+			wantErr: "rerankConfig.documents does not reference a list of strings",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			documentsRef, err := sdk.NewReferenceResolver(".Payload.After.documents")
+			is.NoErr(err)
+
+			p := &Processor{documentsRef: &documentsRef}
+			record := opencdc.Record{
+				Payload: opencdc.Change{After: opencdc.StructuredData{"documents": tc.val}},
+			}
+
+			docs, err := p.rerankDocuments(&record)
+			if tc.wantErr == "" {
+				is.NoErr(err)
+				is.Equal(docs, tc.wantDocs)
+			} else {
+				is.True(err != nil)
+				is.Equal(err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessor_rerankDocuments_usesDocumentsListWhenNoRefConfigured(t *testing.T) {
+	is := is.New(t)
+
+	p := &Processor{
+		config: ProcessorConfig{
+			RerankConfig: &RerankConfig{DocumentsList: []string{"doc1", "doc2"}},
+		},
+	}
+
+	docs, err := p.rerankDocuments(&opencdc.Record{})
+	is.NoErr(err)
+	is.Equal(docs, []string{"doc1", "doc2"})
+}
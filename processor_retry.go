@@ -0,0 +1,121 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	sdk "github.com/conduitio/conduit-processor-sdk"
+)
+
+// callWithRetry calls fn, retrying it with full-jitter exponential backoff while the error it
+// returns is a retryable Cohere error, up to backoffRetry.count times. It is shared by the
+// Command, Embed and Rerank model paths so they classify and back off identically.
+func (p *Processor) callWithRetry(ctx context.Context, fn func() error) error {
+	defer p.backoffCfg.Reset() // reset for the next call, whether this one succeeded or not
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if !isRetryableError(err) {
+			return err
+		}
+
+		attempt := p.backoffCfg.Attempt()
+		if attempt >= p.config.BackoffRetryCount {
+			return err
+		}
+
+		duration := p.backoffDuration(attempt)
+		if ra, ok := retryAfter(err); ok {
+			duration = ra
+		}
+		p.backoffCfg.Duration() // advance the attempt counter
+
+		sdk.Logger(ctx).Debug().
+			Err(err).
+			Float64("attempt", attempt).
+			Float64("backoffRetry.count", p.config.BackoffRetryCount).
+			Int64("backoffRetry.duration", duration.Milliseconds()).
+			Msg("retrying Cohere HTTP request")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(duration):
+		}
+	}
+}
+
+// isRetryableError reports whether err is a Cohere error worth retrying.
+func isRetryableError(err error) bool {
+	var gatewayTimeout *cohere.GatewayTimeoutError
+	var internalServer *cohere.InternalServerError
+	var serviceUnavailable *cohere.ServiceUnavailableError
+	var tooManyRequests *cohere.TooManyRequestsError
+
+	switch {
+	case errors.As(err, &gatewayTimeout),
+		errors.As(err, &internalServer),
+		errors.As(err, &serviceUnavailable),
+		errors.As(err, &tooManyRequests):
+		return true
+	default:
+		// BadRequestError, ClientClosedRequestError, ForbiddenError, InvalidTokenError,
+		// NotFoundError, NotImplementedError, UnauthorizedError, UnprocessableEntityError
+		return false
+	}
+}
+
+// backoffDuration computes the full-jitter backoff wait for the given attempt: a uniform random
+// duration between 0 and min(backoffRetry.max, backoffRetry.min * backoffRetry.factor^attempt).
+// When backoffRetry.jitter is disabled, the deterministic cap itself is returned instead.
+func (p *Processor) backoffDuration(attempt float64) time.Duration {
+	maxWait := p.config.BackoffRetryMax
+	if capf := float64(p.config.BackoffRetryMin) * math.Pow(p.config.BackoffRetryFactor, attempt); capf < float64(maxWait) {
+		maxWait = time.Duration(capf)
+	}
+	if !p.config.BackoffRetryJitter || maxWait <= 0 {
+		return maxWait
+	}
+	return time.Duration(rand.Int63n(int64(maxWait) + 1))
+}
+
+// retryAfter extracts the wait duration from a TooManyRequestsError's Retry-After header, if set.
+func retryAfter(err error) (time.Duration, bool) {
+	var tooManyRequests *cohere.TooManyRequestsError
+	if !errors.As(err, &tooManyRequests) || tooManyRequests.Header == nil {
+		return 0, false
+	}
+
+	value := tooManyRequests.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 Meroxa, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cohere "github.com/cohere-ai/cohere-go/v2"
+	"github.com/matryer/is"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "gateway timeout is retryable", err: &cohere.GatewayTimeoutError{}, retryable: true},
+		{name: "internal server error is retryable", err: &cohere.InternalServerError{}, retryable: true},
+		{name: "service unavailable is retryable", err: &cohere.ServiceUnavailableError{}, retryable: true},
+		{name: "too many requests is retryable", err: &cohere.TooManyRequestsError{}, retryable: true},
+		{name: "bad request is not retryable", err: &cohere.BadRequestError{}, retryable: false},
+		{name: "unauthorized is not retryable", err: &cohere.UnauthorizedError{}, retryable: false},
+		{name: "plain error is not retryable", err: errors.New("boom"), retryable: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(isRetryableError(tc.err), tc.retryable)
+		})
+	}
+}
+
+func TestProcessor_backoffDuration(t *testing.T) {
+	is := is.New(t)
+
+	p := &Processor{
+		config: ProcessorConfig{
+			BackoffRetryMin:    0,
+			BackoffRetryMax:    0,
+			BackoffRetryFactor: 2,
+			BackoffRetryJitter: false,
+		},
+	}
+	p.backoffCfg = newBackoffCfg(p.config)
+
+	// with min and max both 0, the deterministic cap is always 0, jitter or not.
+	is.Equal(p.backoffDuration(0), time.Duration(0))
+}
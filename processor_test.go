@@ -136,6 +136,86 @@ func TestProcessor_Configure(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "invalid modelVersion for rerank model returns error",
+			config: config.Config{
+				"apiKey":       "api-key",
+				"model":        "rerank",
+				"modelVersion": "rerank-v2",
+			},
+			wantErr: `error validating configuration: invalid modelVersion for rerank model: rerank-v2`,
+		},
+		{
+			name: "rerank model without rerankConfig returns error",
+			config: config.Config{
+				"apiKey":       "api-key",
+				"model":        "rerank",
+				"modelVersion": "rerank-english-v3.0",
+			},
+			wantErr: `error validating configuration: rerankConfig is required when model is 'rerank'`,
+		},
+		{
+			name: "rerank model without rerankConfig.query returns error",
+			config: config.Config{
+				"apiKey":                     "api-key",
+				"model":                      "rerank",
+				"modelVersion":               "rerank-english-v3.0",
+				"rerankConfig.documentsList": "doc1,doc2",
+			},
+			wantErr: `error validating configuration: rerankConfig.query is required`,
+		},
+		{
+			name: "rerank model without documents or documentsList returns error",
+			config: config.Config{
+				"apiKey":             "api-key",
+				"model":              "rerank",
+				"modelVersion":       "rerank-english-v3.0",
+				"rerankConfig.query": ".Payload.After.query",
+			},
+			wantErr: `error validating configuration: either rerankConfig.documents or rerankConfig.documentsList must be provided`,
+		},
+		{
+			name: "valid rerank model configuration",
+			config: config.Config{
+				"apiKey":                     "api-key",
+				"model":                      "rerank",
+				"modelVersion":               "rerank-english-v3.0",
+				"rerankConfig.query":         ".Payload.After.query",
+				"rerankConfig.documentsList": "doc1,doc2",
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid authScheme returns error",
+			config: config.Config{
+				"apiKey":       "api-key",
+				"model":        "command",
+				"modelVersion": "command",
+				"authScheme":   "basic",
+			},
+			wantErr: `error validating configuration: invalid authScheme: basic`,
+		},
+		{
+			name: "relative baseURL returns error",
+			config: config.Config{
+				"apiKey":       "api-key",
+				"model":        "command",
+				"modelVersion": "command",
+				"baseURL":      "/not-absolute",
+			},
+			wantErr: `error validating configuration: baseURL must be an absolute URL: /not-absolute`,
+		},
+		{
+			name: "valid baseURL for a self-hosted deployment",
+			config: config.Config{
+				"apiKey":       "api-key",
+				"model":        "command",
+				"modelVersion": "command",
+				"baseURL":      "https://my-deployment.openai.azure.com",
+				"authScheme":   "api-key",
+			},
+			wantErr: "",
+		},
 	}
 
 	for _, tc := range tests {